@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ParsonsCorp/atlassian_status_exporter/collector"
+)
+
+// targetCollector runs every enabled collector.Collector against a single
+// Atlassian target and reports their metrics.
+type targetCollector struct {
+	target     Target
+	collectors []collector.Collector
+}
+
+// newTargetCollector builds a targetCollector for target, instantiating one
+// collector.Collector per name in enabled.
+func newTargetCollector(target Target, enabled []string) (*targetCollector, error) {
+	client := &http.Client{Timeout: time.Duration(target.Timeout) * time.Second}
+
+	// cookie auth needs a jar to carry the JSESSIONID established by the
+	// /dologin.action handshake across every subsequent request.
+	if target.Auth.Mode == string(collector.AuthCookie) {
+		jar, err := cookiejar.New(nil)
+		if err != nil {
+			return nil, fmt.Errorf("building cookie jar for target %q: %w", target.Name, err)
+		}
+		client.Jar = jar
+	}
+
+	cfg := collector.Config{
+		Name:     target.Name,
+		Protocol: target.Protocol,
+		Host:     target.Host,
+		Timeout:  time.Duration(target.Timeout) * time.Second,
+		Client:   client,
+		Auth: collector.Auth{
+			Mode:     collector.AuthMode(target.Auth.Mode),
+			Username: target.Auth.Username,
+			Password: target.Auth.Password,
+			Token:    target.Auth.Token,
+		},
+		Labels: target.Labels,
+	}
+
+	cs := make([]collector.Collector, 0, len(enabled))
+	for _, name := range enabled {
+		factory, ok := collector.Factories[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown collector %q", name)
+		}
+		c, err := factory(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("building collector %q for target %q: %w", name, target.Name, err)
+		}
+		cs = append(cs, c)
+	}
+
+	return &targetCollector{target: target, collectors: cs}, nil
+}
+
+// Describe implements prometheus.Collector. Every metric this exporter emits
+// uses dynamic labels via MustNewConstMetric, so Describe is left empty and
+// the registry is used in unchecked collector mode, same as the collector
+// subpackage's per-endpoint implementations.
+func (t *targetCollector) Describe(ch chan<- *prometheus.Desc) {}
+
+// Collect implements prometheus.Collector, running every enabled collector
+// for this target in turn.
+func (t *targetCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, c := range t.collectors {
+		collector.Execute(t.target.Name, c, ch)
+	}
+}
+
+// devicesCollector is the top-level prometheus.Collector registered with the
+// exporter. It fans out across every configured target and scrapes them
+// concurrently, mirroring the "one Config, many Devices" pattern used by
+// multi-device exporters like mikrotik-exporter.
+//
+// The set of per-target collectors is held behind a mutex so a config
+// reload can swap it out atomically while a scrape is in flight.
+type devicesCollector struct {
+	enabled []string
+
+	mu         sync.RWMutex
+	collectors map[string]*targetCollector
+}
+
+// newDevicesCollector builds one targetCollector per target in cfg, each
+// running the given set of enabled collectors.
+func newDevicesCollector(cfg *Config, enabled []string) (*devicesCollector, error) {
+	d := &devicesCollector{enabled: enabled}
+	if err := d.Reload(cfg); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Reload rebuilds the collector for every target in cfg and atomically
+// swaps it in as the active set, dropping any target that is no longer
+// present and adding any that are new.
+func (d *devicesCollector) Reload(cfg *Config) error {
+	collectors := make(map[string]*targetCollector, len(cfg.Targets))
+	for _, target := range cfg.Targets {
+		tc, err := newTargetCollector(target, d.enabled)
+		if err != nil {
+			return err
+		}
+		collectors[target.Name] = tc
+	}
+
+	d.mu.Lock()
+	d.collectors = collectors
+	d.mu.Unlock()
+
+	return nil
+}
+
+// Describe implements prometheus.Collector.
+func (d *devicesCollector) Describe(ch chan<- *prometheus.Desc) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	for _, c := range d.collectors {
+		c.Describe(ch)
+	}
+}
+
+// Collect implements prometheus.Collector, scraping every target in parallel
+// and waiting for them all to finish before returning.
+func (d *devicesCollector) Collect(ch chan<- prometheus.Metric) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	wg.Add(len(d.collectors))
+	for _, c := range d.collectors {
+		go func(c *targetCollector) {
+			defer wg.Done()
+			c.Collect(ch)
+		}(c)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,133 @@
+package collector
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestNewRequestBasicAuth(t *testing.T) {
+	cfg := Config{
+		Name:   "t",
+		Client: &http.Client{},
+		Auth:   Auth{Mode: AuthBasic, Username: "alice", Password: "hunter2"},
+	}
+
+	req, err := cfg.NewRequest(http.MethodGet, "https://jira.example.com/status")
+	if err != nil {
+		t.Fatalf("NewRequest returned an error: %v", err)
+	}
+
+	user, pass, ok := req.BasicAuth()
+	if !ok || user != "alice" || pass != "hunter2" {
+		t.Errorf("expected basic auth alice/hunter2, got %q/%q (ok=%v)", user, pass, ok)
+	}
+}
+
+func TestNewRequestBearerAuth(t *testing.T) {
+	cfg := Config{
+		Name:   "t",
+		Client: &http.Client{},
+		Auth:   Auth{Mode: AuthBearer, Token: "my-token"},
+	}
+
+	req, err := cfg.NewRequest(http.MethodGet, "https://jira.example.com/status")
+	if err != nil {
+		t.Fatalf("NewRequest returned an error: %v", err)
+	}
+
+	if got := req.Header.Get("Authorization"); got != "Bearer my-token" {
+		t.Errorf("expected Authorization header %q, got %q", "Bearer my-token", got)
+	}
+}
+
+func TestNewRequestCookieAuthLogsIn(t *testing.T) {
+	var loginRequests int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/dologin.action" {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			return
+		}
+		loginRequests++
+		http.SetCookie(w, &http.Cookie{Name: "JSESSIONID", Value: "abc123"})
+	}))
+	defer server.Close()
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatalf("creating cookie jar: %v", err)
+	}
+
+	cfg := configForTestServer(t, server, jar)
+
+	if _, err := cfg.NewRequest(http.MethodGet, server.URL+"/status"); err != nil {
+		t.Fatalf("NewRequest returned an error: %v", err)
+	}
+	if loginRequests != 1 {
+		t.Fatalf("expected exactly 1 login request, got %d", loginRequests)
+	}
+
+	// A second request should reuse the existing session cookie rather than
+	// logging in again.
+	if _, err := cfg.NewRequest(http.MethodGet, server.URL+"/status"); err != nil {
+		t.Fatalf("NewRequest returned an error: %v", err)
+	}
+	if loginRequests != 1 {
+		t.Errorf("expected no re-login once a session cookie exists, got %d login requests", loginRequests)
+	}
+}
+
+func TestEnsureSessionRequiresCookieJar(t *testing.T) {
+	cfg := Config{
+		Name:   "t",
+		Host:   "jira.example.com",
+		Auth:   Auth{Mode: AuthCookie, Username: "alice", Password: "hunter2"},
+		Client: &http.Client{},
+	}
+
+	err := cfg.ensureSession()
+	if err == nil || !strings.Contains(err.Error(), "CookieJar") {
+		t.Fatalf("expected a CookieJar error, got %v", err)
+	}
+}
+
+func TestEnsureSessionFailsWithoutSetCookie(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// login "succeeds" but never sets a session cookie
+	}))
+	defer server.Close()
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatalf("creating cookie jar: %v", err)
+	}
+
+	cfg := configForTestServer(t, server, jar)
+
+	if err := cfg.ensureSession(); err == nil {
+		t.Fatal("expected an error when login establishes no session cookie")
+	}
+}
+
+// configForTestServer builds a Config pointed at an httptest.Server, using
+// the server's own host/protocol so NewRequest/ensureSession hit it directly.
+func configForTestServer(t *testing.T, server *httptest.Server, jar http.CookieJar) Config {
+	t.Helper()
+
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("parsing test server URL: %v", err)
+	}
+
+	return Config{
+		Name:     "t",
+		Protocol: u.Scheme,
+		Host:     u.Host,
+		Client:   &http.Client{Jar: jar},
+		Auth:     Auth{Mode: AuthCookie, Username: "alice", Password: "hunter2"},
+	}
+}
@@ -0,0 +1,94 @@
+package collector
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func init() {
+	registerFactory("cluster", newClusterCollector)
+}
+
+// clusterNode is a single entry in the /rest/api/latest/cluster node roster.
+type clusterNode struct {
+	NodeID string `json:"nodeId"`
+	State  string `json:"state"`
+}
+
+// clusterResponse is the subset of /rest/api/latest/cluster we care about.
+type clusterResponse struct {
+	Nodes []clusterNode `json:"nodes"`
+}
+
+// clusterCollector scrapes the cluster node roster exposed by clustered Jira
+// and Confluence instances (they share the same /rest/api/latest/cluster
+// shape) and reports each node's state.
+//
+// Bitbucket and Bamboo expose their cluster roster under a different path
+// and response shape and are not supported; a target labeled with either of
+// those products gets a clear error instead of a Jira-path 404.
+type clusterCollector struct {
+	cfg Config
+
+	nodeUpMetric *prometheus.Desc
+}
+
+// newClusterCollector is the Factories constructor for the cluster collector.
+func newClusterCollector(cfg Config) (Collector, error) {
+	return &clusterCollector{
+		cfg: cfg,
+		nodeUpMetric: prometheus.NewDesc(
+			"atlassian_status_cluster_node_up",
+			"metric reports 1 when a cluster node's state is ACTIVE, 0 otherwise",
+			[]string{"node_id", "state", "target"},
+			cfg.Labels,
+		),
+	}, nil
+}
+
+// Name implements Collector.
+func (c *clusterCollector) Name() string { return "cluster" }
+
+// Update implements Collector.
+func (c *clusterCollector) Update(ch chan<- prometheus.Metric) error {
+	if product, ok := c.cfg.Labels["product"]; ok && product != "jira" && product != "confluence" {
+		return fmt.Errorf("cluster collector only supports product=jira or product=confluence targets, target %q is labeled product=%s", c.cfg.Name, product)
+	}
+
+	url := c.cfg.Protocol + "://" + c.cfg.Host + "/rest/api/latest/cluster"
+
+	req, err := c.cfg.NewRequest(http.MethodGet, url)
+	if err != nil {
+		return fmt.Errorf("building request for %s: %w", url, err)
+	}
+
+	resp, err := c.cfg.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("GET %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading body from %s: %w", url, err)
+	}
+
+	var cluster clusterResponse
+	if err := json.Unmarshal(body, &cluster); err != nil {
+		return fmt.Errorf("unmarshalling cluster response: %w", err)
+	}
+
+	for _, node := range cluster.Nodes {
+		up := 0.0
+		if node.State == "ACTIVE" {
+			up = 1
+		}
+		ch <- prometheus.MustNewConstMetric(c.nodeUpMetric, prometheus.GaugeValue, up, node.NodeID, node.State, c.cfg.Name)
+	}
+
+	return nil
+}
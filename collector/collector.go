@@ -0,0 +1,93 @@
+// Package collector implements the pluggable set of per-endpoint Atlassian
+// collectors used by the exporter, modeled on the Factories registry pattern
+// from node_exporter.
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+// Config carries everything a Collector needs to scrape a single target.
+type Config struct {
+	Name     string
+	Protocol string
+	Host     string
+	Timeout  time.Duration
+	Client   *http.Client
+	Auth     Auth
+	// Labels are applied as ConstLabels on every descriptor a Collector
+	// creates, so operators can tell instances apart with e.g. product=jira.
+	Labels map[string]string
+}
+
+// Collector is implemented by every pluggable Atlassian endpoint collector.
+type Collector interface {
+	// Update scrapes the target described by the Collector's Config and
+	// sends the resulting metrics down ch.
+	Update(ch chan<- prometheus.Metric) error
+	// Name returns the collector's unique, flag-friendly name.
+	Name() string
+}
+
+// Factories holds a constructor for every collector that can be enabled via
+// -collectors.enabled. Each collector implementation registers itself here
+// from an init() function.
+var Factories = map[string]func(cfg Config) (Collector, error){}
+
+// registerFactory is called by each collector implementation's init() to add
+// itself to Factories.
+func registerFactory(name string, factory func(cfg Config) (Collector, error)) {
+	if _, ok := Factories[name]; ok {
+		panic(fmt.Sprintf("collector %q registered twice", name))
+	}
+	Factories[name] = factory
+}
+
+var (
+	scrapeDurationDesc = prometheus.NewDesc(
+		"atlassian_status_scrape_collector_duration_seconds",
+		"atlassian_status_exporter: duration of a collector scrape.",
+		[]string{"collector", "target"},
+		nil,
+	)
+	scrapeSuccessDesc = prometheus.NewDesc(
+		"atlassian_status_scrape_collector_success",
+		"atlassian_status_exporter: whether a collector succeeded.",
+		[]string{"collector", "target"},
+		nil,
+	)
+)
+
+// requireProduct returns an error unless cfg's product label (if any) is
+// product. Collectors that only understand one Atlassian product's REST
+// shape call this at the top of Update so a mismatched target fails fast
+// with a clear reason instead of hitting the wrong path and 404ing.
+// Targets with no product label are assumed compatible.
+func requireProduct(cfg Config, product string) error {
+	if p, ok := cfg.Labels["product"]; ok && p != product {
+		return fmt.Errorf("collector only supports product=%s targets, target %q is labeled product=%s", product, cfg.Name, p)
+	}
+	return nil
+}
+
+// Execute runs c.Update against target, wrapping the call with the shared
+// scrape-duration and scrape-success metrics every collector reports.
+func Execute(target string, c Collector, ch chan<- prometheus.Metric) {
+	start := time.Now()
+	err := c.Update(ch)
+	duration := time.Since(start).Seconds()
+
+	success := 1.0
+	if err != nil {
+		log.Error("collector ", c.Name(), " for target ", target, " failed: ", err)
+		success = 0
+	}
+
+	ch <- prometheus.MustNewConstMetric(scrapeDurationDesc, prometheus.GaugeValue, duration, c.Name(), target)
+	ch <- prometheus.MustNewConstMetric(scrapeSuccessDesc, prometheus.GaugeValue, success, c.Name(), target)
+}
@@ -0,0 +1,88 @@
+package collector
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func init() {
+	registerFactory("plugins", newPluginsCollector)
+}
+
+// pluginsResponse is the subset of /rest/plugins/1.0/ we care about.
+type pluginsResponse struct {
+	Plugins []struct {
+		Key     string `json:"key"`
+		Enabled bool   `json:"enabled"`
+	} `json:"plugins"`
+}
+
+// pluginsCollector scrapes /rest/plugins/1.0/, the Universal Plugin Manager
+// REST API shared by every UPM-enabled Atlassian product (Jira, Confluence,
+// Bitbucket, and Bamboo all expose it at the same path), and reports how
+// many plugins are enabled vs. disabled.
+type pluginsCollector struct {
+	cfg Config
+
+	countMetric *prometheus.Desc
+}
+
+// newPluginsCollector is the Factories constructor for the plugins collector.
+func newPluginsCollector(cfg Config) (Collector, error) {
+	return &pluginsCollector{
+		cfg: cfg,
+		countMetric: prometheus.NewDesc(
+			"atlassian_status_plugins_total",
+			"metric reports the number of installed plugins, broken down by enabled state",
+			[]string{"enabled", "target"},
+			cfg.Labels,
+		),
+	}, nil
+}
+
+// Name implements Collector.
+func (c *pluginsCollector) Name() string { return "plugins" }
+
+// Update implements Collector.
+func (c *pluginsCollector) Update(ch chan<- prometheus.Metric) error {
+	url := c.cfg.Protocol + "://" + c.cfg.Host + "/rest/plugins/1.0/"
+
+	req, err := c.cfg.NewRequest(http.MethodGet, url)
+	if err != nil {
+		return fmt.Errorf("building request for %s: %w", url, err)
+	}
+
+	resp, err := c.cfg.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("GET %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading body from %s: %w", url, err)
+	}
+
+	var plugins pluginsResponse
+	if err := json.Unmarshal(body, &plugins); err != nil {
+		return fmt.Errorf("unmarshalling plugins response: %w", err)
+	}
+
+	var enabled, disabled float64
+	for _, p := range plugins.Plugins {
+		if p.Enabled {
+			enabled++
+		} else {
+			disabled++
+		}
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.countMetric, prometheus.GaugeValue, enabled, "true", c.cfg.Name)
+	ch <- prometheus.MustNewConstMetric(c.countMetric, prometheus.GaugeValue, disabled, "false", c.cfg.Name)
+
+	return nil
+}
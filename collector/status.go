@@ -0,0 +1,147 @@
+package collector
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func init() {
+	registerFactory("status", newStatusCollector)
+}
+
+// statusEndpoint defines the expected response json structure found at /status.
+type statusEndpoint struct {
+	State string `json:"state"`
+}
+
+// statusCollector scrapes the /status endpoint common to every Atlassian
+// application and reports the application's lifecycle state.
+type statusCollector struct {
+	cfg Config
+
+	scrapeUpMetric *prometheus.Desc
+	stateMetric    *prometheus.Desc
+}
+
+// newStatusCollector is the Factories constructor for the status collector.
+func newStatusCollector(cfg Config) (Collector, error) {
+	return &statusCollector{
+		cfg: cfg,
+		scrapeUpMetric: prometheus.NewDesc(
+			"atlassian_status_scrape_url_up",
+			"metric shows the status of the connection to the atlassian application endpoint",
+			[]string{"httpcode", "url", "target"},
+			cfg.Labels,
+		),
+		stateMetric: prometheus.NewDesc(
+			"atlassian_status_state",
+			"metric returns the state of the monitored atlassian application",
+			[]string{"state", "httpcode", "description", "url", "target"},
+			cfg.Labels,
+		),
+	}, nil
+}
+
+// Name implements Collector.
+func (c *statusCollector) Name() string { return "status" }
+
+// Update implements Collector.
+func (c *statusCollector) Update(ch chan<- prometheus.Metric) error {
+	url := c.cfg.Protocol + "://" + c.cfg.Host + "/status"
+
+	req, err := c.cfg.NewRequest(http.MethodGet, url)
+	if err != nil {
+		ch <- prometheus.MustNewConstMetric(c.scrapeUpMetric, prometheus.GaugeValue, 0, "", c.cfg.Host, c.cfg.Name)
+		return fmt.Errorf("building request for %s: %w", url, err)
+	}
+
+	resp, err := c.cfg.Client.Do(req)
+	if err != nil {
+		ch <- prometheus.MustNewConstMetric(c.scrapeUpMetric, prometheus.GaugeValue, 0, "", c.cfg.Host, c.cfg.Name)
+		return fmt.Errorf("GET %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	ch <- prometheus.MustNewConstMetric(c.scrapeUpMetric, prometheus.GaugeValue, 1, strconv.Itoa(resp.StatusCode), c.cfg.Host, c.cfg.Name)
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		// still emit a degraded state metric so dashboards/alerts keep a
+		// continuous series even when the body can't be read.
+		ch <- prometheus.MustNewConstMetric(c.stateMetric, prometheus.GaugeValue, stateMetricValue(""), "", strconv.Itoa(resp.StatusCode), stateDesc(""), c.cfg.Host, c.cfg.Name)
+		return fmt.Errorf("reading body from %s: %w", url, err)
+	}
+
+	// remove the trailing \n and any whitespace before checking if we got an empty body
+	if strings.TrimSuffix(strings.Replace(string(body), " ", "", -1), "\n") == "" {
+		ch <- prometheus.MustNewConstMetric(c.stateMetric, prometheus.GaugeValue, stateMetricValue(""), "", strconv.Itoa(resp.StatusCode), stateDesc(""), c.cfg.Host, c.cfg.Name)
+		return nil
+	}
+
+	var m statusEndpoint
+	if err := json.Unmarshal(body, &m); err != nil {
+		// same as above: a non-JSON body (e.g. a proxy error page) is a
+		// degraded state worth alerting on, not a missing series.
+		ch <- prometheus.MustNewConstMetric(c.stateMetric, prometheus.GaugeValue, stateMetricValue(""), "", strconv.Itoa(resp.StatusCode), stateDesc(""), c.cfg.Host, c.cfg.Name)
+		return fmt.Errorf("unmarshalling /status response: %w", err)
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		c.stateMetric,
+		prometheus.GaugeValue,
+		stateMetricValue(m.State),
+		m.State,
+		strconv.Itoa(resp.StatusCode),
+		stateDesc(m.State),
+		c.cfg.Host,
+		c.cfg.Name,
+	)
+
+	return nil
+}
+
+// stateMetricValue takes in the state response entity and returns a code we'll use for the metric value.
+func stateMetricValue(state string) float64 {
+	switch state {
+	case "RUNNING":
+		return 0
+	case "ERROR":
+		return 1
+	case "STARTING":
+		return 2
+	case "STOPPING":
+		return 3
+	case "FIRST_RUN":
+		return 4
+	case "":
+		return 5
+	default:
+		return 6
+	}
+}
+
+// stateDesc takes in the state response entity and returns the description that matches.
+func stateDesc(state string) string {
+	switch state {
+	case "RUNNING":
+		return "Running normally"
+	case "ERROR":
+		return "An error state"
+	case "STARTING":
+		return "Application is starting"
+	case "STOPPING":
+		return "Application is stopping"
+	case "FIRST_RUN":
+		return "Application is running for the first time and has not yet been configured"
+	case "":
+		return "Application failed to start up in an unexpected way (the web application failed to deploy)"
+	default:
+		return "Unknown Response, go look at the Atlassian Application"
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Config is the top-level structure of the YAML file passed via -config.file.
+// It describes every Atlassian instance this exporter should scrape.
+type Config struct {
+	Targets []Target `yaml:"targets"`
+}
+
+// Target describes a single Atlassian instance to monitor.
+type Target struct {
+	Name     string            `yaml:"name"`
+	Protocol string            `yaml:"protocol,omitempty"`
+	Host     string            `yaml:"host"`
+	Timeout  int               `yaml:"timeout,omitempty"`
+	Labels   map[string]string `yaml:"labels,omitempty"`
+	Auth     TargetAuth        `yaml:"auth,omitempty"`
+}
+
+// TargetAuth describes how the exporter should authenticate its requests to
+// a target. Mode selects between "basic", "bearer", and "cookie"; leaving it
+// empty scrapes anonymously, same as before auth support existed.
+type TargetAuth struct {
+	Mode     string `yaml:"mode,omitempty"`
+	Username string `yaml:"username,omitempty"`
+	Password string `yaml:"password,omitempty"`
+	Token    string `yaml:"token,omitempty"`
+}
+
+// LoadConfig reads and parses the YAML config file found at path, filling in
+// any defaults left unset on each target.
+func LoadConfig(path string) (*Config, error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read config file %s: %w", path, err)
+	}
+
+	cfg := &Config{}
+	if err := yaml.Unmarshal(buf, cfg); err != nil {
+		return nil, fmt.Errorf("unable to parse config file %s: %w", path, err)
+	}
+
+	if len(cfg.Targets) == 0 {
+		return nil, fmt.Errorf("no targets defined in config file %s", path)
+	}
+
+	for i := range cfg.Targets {
+		if cfg.Targets[i].Name == "" {
+			return nil, fmt.Errorf("target at index %d is missing a name", i)
+		}
+		if cfg.Targets[i].Host == "" {
+			return nil, fmt.Errorf("target %q is missing a host", cfg.Targets[i].Name)
+		}
+		if cfg.Targets[i].Protocol == "" {
+			cfg.Targets[i].Protocol = "https"
+		}
+		if cfg.Targets[i].Timeout == 0 {
+			cfg.Targets[i].Timeout = *scrapeTimeout
+		}
+
+		// allow secrets to be referenced as ${VAR} rather than committed to
+		// the config file in plain text.
+		cfg.Targets[i].Auth.Username = os.ExpandEnv(cfg.Targets[i].Auth.Username)
+		cfg.Targets[i].Auth.Password = os.ExpandEnv(cfg.Targets[i].Auth.Password)
+		cfg.Targets[i].Auth.Token = os.ExpandEnv(cfg.Targets[i].Auth.Token)
+
+		if err := validateAuth(cfg.Targets[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return cfg, nil
+}
+
+// validateAuth checks that a target's auth block has the fields its mode
+// requires.
+func validateAuth(target Target) error {
+	switch target.Auth.Mode {
+	case "", "basic", "cookie":
+		if target.Auth.Mode != "" && (target.Auth.Username == "" || target.Auth.Password == "") {
+			return fmt.Errorf("target %q: auth mode %q requires username and password", target.Name, target.Auth.Mode)
+		}
+	case "bearer":
+		if target.Auth.Token == "" {
+			return fmt.Errorf("target %q: auth mode \"bearer\" requires a token", target.Name)
+		}
+	default:
+		return fmt.Errorf("target %q: unknown auth mode %q", target.Name, target.Auth.Mode)
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func writeConfig(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yml")
+	if err := ioutil.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("writing test config: %v", err)
+	}
+	return path
+}
+
+func TestLoadConfigDefaults(t *testing.T) {
+	path := writeConfig(t, `
+targets:
+  - name: jira-prod
+    host: jira.example.com
+`)
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig returned an error: %v", err)
+	}
+
+	if len(cfg.Targets) != 1 {
+		t.Fatalf("expected 1 target, got %d", len(cfg.Targets))
+	}
+
+	target := cfg.Targets[0]
+	if target.Protocol != "https" {
+		t.Errorf("expected default protocol https, got %q", target.Protocol)
+	}
+	if target.Timeout != *scrapeTimeout {
+		t.Errorf("expected default timeout %d, got %d", *scrapeTimeout, target.Timeout)
+	}
+}
+
+func TestLoadConfigExpandsAuthEnvVars(t *testing.T) {
+	t.Setenv("TEST_JIRA_PAT", "super-secret-token")
+
+	path := writeConfig(t, `
+targets:
+  - name: jira-prod
+    host: jira.example.com
+    auth:
+      mode: bearer
+      token: ${TEST_JIRA_PAT}
+`)
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig returned an error: %v", err)
+	}
+
+	if got := cfg.Targets[0].Auth.Token; got != "super-secret-token" {
+		t.Errorf("expected expanded token %q, got %q", "super-secret-token", got)
+	}
+}
+
+func TestLoadConfigRejectsMissingFields(t *testing.T) {
+	cases := []struct {
+		name string
+		yaml string
+	}{
+		{"no targets", `targets: []`},
+		{"missing name", "targets:\n  - host: jira.example.com\n"},
+		{"missing host", "targets:\n  - name: jira-prod\n"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := writeConfig(t, tc.yaml)
+			if _, err := LoadConfig(path); err == nil {
+				t.Fatal("expected an error, got nil")
+			}
+		})
+	}
+}
+
+func TestLoadConfigMissingFile(t *testing.T) {
+	if _, err := LoadConfig(filepath.Join(t.TempDir(), "does-not-exist.yml")); err == nil {
+		t.Fatal("expected an error for a missing config file, got nil")
+	}
+}
+
+func TestValidateAuth(t *testing.T) {
+	cases := []struct {
+		name    string
+		target  Target
+		wantErr bool
+	}{
+		{"no auth", Target{Name: "t"}, false},
+		{"basic ok", Target{Name: "t", Auth: TargetAuth{Mode: "basic", Username: "u", Password: "p"}}, false},
+		{"basic missing password", Target{Name: "t", Auth: TargetAuth{Mode: "basic", Username: "u"}}, true},
+		{"cookie missing username", Target{Name: "t", Auth: TargetAuth{Mode: "cookie", Password: "p"}}, true},
+		{"bearer ok", Target{Name: "t", Auth: TargetAuth{Mode: "bearer", Token: "tok"}}, false},
+		{"bearer missing token", Target{Name: "t", Auth: TargetAuth{Mode: "bearer"}}, true},
+		{"unknown mode", Target{Name: "t", Auth: TargetAuth{Mode: "ntlm"}}, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateAuth(tc.target)
+			if tc.wantErr && err == nil {
+				t.Fatal("expected an error, got nil")
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		})
+	}
+}
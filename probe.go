@@ -0,0 +1,52 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// probeHandler implements a blackbox_exporter-style /probe endpoint. It lets
+// a single deployment monitor arbitrary Atlassian URLs listed in
+// Prometheus's scrape_configs via relabeling, instead of requiring every
+// target to be known up front in the static config file.
+func probeHandler(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	target := params.Get("target")
+	if target == "" {
+		http.Error(w, "target parameter is missing", http.StatusBadRequest)
+		return
+	}
+
+	// module selects which comma-separated set of collectors to run for this
+	// probe, defaulting to whatever -collectors.enabled was started with.
+	enabled := *collectorsEnabled
+	if module := params.Get("module"); module != "" {
+		enabled = module
+	}
+	log.Debug("probe request for target ", target, " collectors ", enabled)
+
+	t := Target{
+		Name:     target,
+		Protocol: *probeProtocol,
+		Host:     target,
+		Timeout:  *scrapeTimeout,
+	}
+
+	tc, err := newTargetCollector(t, strings.Split(enabled, ","))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(tc)
+
+	h := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+	h.ServeHTTP(w, r)
+}
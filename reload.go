@@ -0,0 +1,98 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/fsnotify/fsnotify"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	reloadTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: namespace + "_config_reload_total",
+		Help: "Total number of configuration reloads, by result.",
+	}, []string{"result"})
+
+	reloadLastSuccess = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: namespace + "_config_last_reload_success_timestamp_seconds",
+		Help: "Unix timestamp of the last successful configuration reload.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(reloadTotal, reloadLastSuccess)
+}
+
+// watchConfig watches path for changes and also listens for SIGHUP, calling
+// exporter.Reload on either so operators can pick up config changes without
+// restarting the process.
+func watchConfig(path string, exporter *devicesCollector) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.Error("unable to start config file watcher: ", err)
+		return
+	}
+
+	// Watch the containing directory rather than the file itself: editors
+	// and config-management tools commonly replace the file via rename,
+	// which would otherwise orphan a watch on the old inode.
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		log.Error("unable to watch config directory: ", err)
+		return
+	}
+
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+
+	go func() {
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != filepath.Clean(path) {
+					continue
+				}
+				log.Info("config file changed, reloading: ", event)
+				reloadConfig(path, exporter)
+
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				log.Error("config file watcher error: ", err)
+
+			case <-sighup:
+				log.Info("got SIGHUP, reloading config")
+				reloadConfig(path, exporter)
+			}
+		}
+	}()
+}
+
+// reloadConfig re-parses path and, on success, atomically swaps it in as the
+// exporter's active target set.
+func reloadConfig(path string, exporter *devicesCollector) {
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		log.Error("config reload failed: ", err)
+		reloadTotal.WithLabelValues("failure").Inc()
+		return
+	}
+
+	if err := exporter.Reload(cfg); err != nil {
+		log.Error("config reload failed: ", err)
+		reloadTotal.WithLabelValues("failure").Inc()
+		return
+	}
+
+	log.Info("config reload succeeded: ", len(cfg.Targets), " target(s)")
+	reloadTotal.WithLabelValues("success").Inc()
+	reloadLastSuccess.SetToCurrentTime()
+}
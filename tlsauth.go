@@ -0,0 +1,56 @@
+package main
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+// basicAuthHandler wraps next, requiring HTTP basic auth credentials matching
+// user/pass before letting the request through. Credentials are compared in
+// constant time to avoid leaking timing information.
+func basicAuthHandler(next http.Handler, user, pass string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqUser, reqPass, ok := r.BasicAuth()
+		if !ok ||
+			subtle.ConstantTimeCompare([]byte(reqUser), []byte(user)) != 1 ||
+			subtle.ConstantTimeCompare([]byte(reqPass), []byte(pass)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Basic realm="`+namespace+`_exporter"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// buildTLSConfig builds the *tls.Config used to serve /metrics over HTTPS. It
+// returns nil, nil when no cert/key has been configured, meaning the caller
+// should fall back to plain HTTP.
+func buildTLSConfig(certFile, keyFile, clientCAFile string) (*tls.Config, error) {
+	if certFile == "" && keyFile == "" {
+		return nil, nil
+	}
+	if certFile == "" || keyFile == "" {
+		return nil, fmt.Errorf("both -web.tls-cert-file and -web.tls-key-file must be provided")
+	}
+
+	cfg := &tls.Config{}
+
+	if clientCAFile != "" {
+		caCert, err := ioutil.ReadFile(clientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading -web.tls-client-ca: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("no certificates found in -web.tls-client-ca %s", clientCAFile)
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return cfg, nil
+}